@@ -1,20 +1,31 @@
 // cffi.go
-// This file contains the CFFI bindings for the recent_visits.h C header file.
-// It is used to interface with the C code in the recent_visits.c file.
+// This file contains the core VisitManager type. By default it is backed by
+// the C recent_visits store via cgo (see cgo_storage.go); set Options.Backend
+// to BackendGo for the pure-Go store in gostorage.go.
 package cffi
 
-// #include "recent_visits.h"
-import "C"
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
-	"unsafe"
 )
 
+// numShards is the number of lock stripes used to guard per-user access to
+// the underlying store. Visits for users hashing to different shards can
+// proceed concurrently; visits for the same user share a lock.
+const numShards = 64
+
 // VisitManager represents a manager for tracking recent visits.
 type VisitManager struct {
-	// Pointer to the C struct that manages recent visits
-	ptr *C.VisitManager
+	storage Storage
+	metrics *metrics // nil unless WithMetrics was passed to NewVisitManager
+	file    string   // path to the backend's on-disk store, for ExportAll/ImportAll
+
+	shards  [numShards]sync.RWMutex
+	wg      sync.WaitGroup // tracks in-flight operations so Close can drain them
+	closeMu sync.Mutex
+	closed  bool
 }
 
 // Visit represents a single visit and contains the visit ID, URL, text, and time of the visit.
@@ -25,80 +36,313 @@ type Visit struct {
 	Time    time.Time
 }
 
-// NewVisitManager creates a new VisitManager instance.
-func NewVisitManager(file string, maxVisitsPerUser int) (*VisitManager, error) {
-	// Convert Go string to C string
-	cFile := C.CString(file)
-	defer C.free(unsafe.Pointer(cFile))
+// NewVisitManager creates a new VisitManager instance using the backend and
+// settings described by opts, applying any optFns (such as WithMetrics)
+// afterward.
+func NewVisitManager(opts Options, optFns ...Option) (*VisitManager, error) {
+	storage, err := newStorage(opts)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create a new VisitManager instance using the C function
-	ptr := C.VisitManagerCreate(cFile, C.size_t(maxVisitsPerUser))
-	if ptr == nil {
-		return nil, fmt.Errorf("failed to create VisitManager")
+	vm := &VisitManager{storage: storage, file: opts.File}
+	for _, fn := range optFns {
+		fn(vm)
 	}
-	return &VisitManager{ptr: ptr}, nil
+	return vm, nil
+}
+
+// shardFor returns the lock stripe responsible for userID.
+func (vm *VisitManager) shardFor(userID uint32) *sync.RWMutex {
+	return &vm.shards[userID%numShards]
 }
 
-// Close releases the resources of the VisitManager.
+// enter registers an in-flight operation with the manager, returning an error
+// if the manager has already been closed. Every call to enter must be paired
+// with a call to vm.wg.Done().
+func (vm *VisitManager) enter() error {
+	vm.closeMu.Lock()
+	defer vm.closeMu.Unlock()
+	if vm.closed {
+		return fmt.Errorf("cffi: VisitManager is closed")
+	}
+	vm.wg.Add(1)
+	return nil
+}
+
+// Close releases the resources of the VisitManager. It drains any in-flight
+// operations before closing the underlying storage and is safe to call more
+// than once.
 func (vm *VisitManager) Close() {
-	if vm.ptr != nil {
-		C.VisitManagerFree(vm.ptr)
-		vm.ptr = nil
+	vm.closeMu.Lock()
+	if vm.closed {
+		vm.closeMu.Unlock()
+		return
 	}
+	vm.closed = true
+	vm.closeMu.Unlock()
+
+	vm.wg.Wait()
+	vm.storage.Close()
 }
 
 // AddVisit adds a visit for a user.
 func (vm *VisitManager) AddVisit(userID, visitID uint32, url, text string) bool {
-	cURL := C.CString(url)
-	defer C.free(unsafe.Pointer(cURL))
-	cText := C.CString(text)
-	defer C.free(unsafe.Pointer(cText))
+	_, span := startSpan(context.Background(), "AddVisit", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return false
+	}
+	defer vm.wg.Done()
+
+	shard := vm.shardFor(userID)
+	shard.Lock()
+	defer shard.Unlock()
+
+	ok := vm.storage.AddVisit(userID, visitID, url, text)
+	vm.metrics.observeLatency("AddVisit", start)
+	if ok {
+		vm.metrics.recordAdd()
+	}
+	return ok
+}
+
+// AddVisitCtx is the context-aware variant of AddVisit. The storage call
+// runs on a worker goroutine; if ctx is canceled or its deadline expires
+// before the call completes, AddVisitCtx returns ctx.Err() immediately. The
+// underlying call is left to finish in the background; it still counts as
+// in flight, so Close waits for it before closing the storage.
+func (vm *VisitManager) AddVisitCtx(ctx context.Context, userID, visitID uint32, url, text string) (bool, error) {
+	ctx, span := startSpan(ctx, "AddVisit", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return false, err
+	}
 
-	// Call the C function to add a visit
-	return bool(C.VisitManagerAddVisit(vm.ptr, C.uint32_t(userID), C.uint32_t(visitID), cURL, cText))
+	done := make(chan bool, 1)
+	go func() {
+		defer vm.wg.Done()
+
+		shard := vm.shardFor(userID)
+		shard.Lock()
+		defer shard.Unlock()
+
+		done <- vm.storage.AddVisit(userID, visitID, url, text)
+	}()
+
+	select {
+	case ok := <-done:
+		vm.metrics.observeLatency("AddVisit", start)
+		if ok {
+			vm.metrics.recordAdd()
+		}
+		return ok, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
 }
 
 // GetRecentVisits returns recent visits for a user.
 func (vm *VisitManager) GetRecentVisits(userID uint32) ([]Visit, error) {
-	var count C.size_t
-
-	// The pointer is owned by the manager and must not be freed here.
-	visitPtrs := C.VisitManagerGetRecentVisits(vm.ptr, C.uint32_t(userID), &count)
-	if visitPtrs == nil || count == 0 {
-		return nil, nil // No visits found
-	}
-
-	// Convert the C array into a Go slice of *C.Visit
-	// Each visit is assumed to be a C.Visit struct in a contiguous block
-	visitsC := (*[1 << 30]*C.Visit)(unsafe.Pointer(visitPtrs))[:count:count]
-
-	// Convert each *C.Visit to a Go Visit
-	visits := make([]Visit, count)
-	for i := range visits {
-		cVisit := visitsC[i]
-		visits[i] = Visit{
-			VisitID: uint32(cVisit.visit_id),
-			URL:     C.GoString(cVisit.url),
-			Text:    C.GoString(cVisit.text),
-			Time:    time.Unix(int64(cVisit.time.tv_sec), int64(cVisit.time.tv_nsec)),
-		}
+	_, span := startSpan(context.Background(), "GetRecentVisits", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return nil, err
+	}
+	defer vm.wg.Done()
+
+	shard := vm.shardFor(userID)
+	shard.RLock()
+	defer shard.RUnlock()
+
+	visits, err := vm.storage.RecentVisits(userID)
+	vm.metrics.observeLatency("GetRecentVisits", start)
+	vm.metrics.setUserVisits(userID, len(visits))
+	span.SetAttributes(resultCountAttr(len(visits)))
+	return visits, err
+}
+
+// GetRecentVisitsCtx is the context-aware variant of GetRecentVisits.
+func (vm *VisitManager) GetRecentVisitsCtx(ctx context.Context, userID uint32) ([]Visit, error) {
+	ctx, span := startSpan(ctx, "GetRecentVisits", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return nil, err
 	}
 
-	return visits, nil
+	type result struct {
+		visits []Visit
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer vm.wg.Done()
+
+		shard := vm.shardFor(userID)
+		shard.RLock()
+		defer shard.RUnlock()
+
+		visits, err := vm.storage.RecentVisits(userID)
+		done <- result{visits, err}
+	}()
+
+	select {
+	case r := <-done:
+		vm.metrics.observeLatency("GetRecentVisits", start)
+		vm.metrics.setUserVisits(userID, len(r.visits))
+		span.SetAttributes(resultCountAttr(len(r.visits)))
+		return r.visits, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GetRecentVisitsPage returns up to limit visits for userID, newest first,
+// starting at the given 0-based offset. Unlike GetRecentVisits it is meant
+// for callers that only need a window of a user's visits; the shard lock is
+// held only for the duration of this call, not across a full iteration (see
+// IterateVisits).
+func (vm *VisitManager) GetRecentVisitsPage(userID uint32, offset, limit int) ([]Visit, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	_, span := startSpan(context.Background(), "GetRecentVisitsPage", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return nil, err
+	}
+	defer vm.wg.Done()
+
+	shard := vm.shardFor(userID)
+	shard.RLock()
+	page, err := vm.storage.RecentVisitsPage(userID, offset, limit)
+	shard.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	vm.metrics.observeLatency("GetRecentVisitsPage", start)
+	span.SetAttributes(resultCountAttr(len(page)))
+	return page, nil
 }
 
 // DeleteVisits deletes the specified visits for a user.
 func (vm *VisitManager) DeleteVisits(userID uint32, visitIDs []uint32) bool {
-	if len(visitIDs) == 0 {
-		return true
+	_, span := startSpan(context.Background(), "DeleteVisits", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return false
 	}
+	defer vm.wg.Done()
+
+	shard := vm.shardFor(userID)
+	shard.Lock()
+	defer shard.Unlock()
 
-	// Convert the visit IDs to a C array
-	cVisitIDs := (*C.uint32_t)(unsafe.Pointer(&visitIDs[0]))
-	return bool(C.VisitManagerDelete(vm.ptr, C.uint32_t(userID), cVisitIDs, C.size_t(len(visitIDs))))
+	ok := vm.storage.Delete(userID, visitIDs)
+	vm.metrics.observeLatency("DeleteVisits", start)
+	if ok {
+		vm.metrics.recordDelete(len(visitIDs))
+	}
+	return ok
+}
+
+// DeleteVisitsCtx is the context-aware variant of DeleteVisits.
+func (vm *VisitManager) DeleteVisitsCtx(ctx context.Context, userID uint32, visitIDs []uint32) (bool, error) {
+	ctx, span := startSpan(ctx, "DeleteVisits", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return false, err
+	}
+
+	done := make(chan bool, 1)
+	go func() {
+		defer vm.wg.Done()
+
+		shard := vm.shardFor(userID)
+		shard.Lock()
+		defer shard.Unlock()
+
+		done <- vm.storage.Delete(userID, visitIDs)
+	}()
+
+	select {
+	case ok := <-done:
+		vm.metrics.observeLatency("DeleteVisits", start)
+		if ok {
+			vm.metrics.recordDelete(len(visitIDs))
+		}
+		return ok, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
 }
 
 // ClearUser clears all visits for a user.
 func (vm *VisitManager) ClearUser(userID uint32) {
-	C.VisitManagerClear(vm.ptr, C.uint32_t(userID))
+	_, span := startSpan(context.Background(), "ClearUser", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return
+	}
+	defer vm.wg.Done()
+
+	shard := vm.shardFor(userID)
+	shard.Lock()
+	defer shard.Unlock()
+
+	vm.storage.Clear(userID)
+	vm.metrics.observeLatency("ClearUser", start)
+	vm.metrics.recordClear()
+	vm.metrics.setUserVisits(userID, 0)
+}
+
+// ClearUserCtx is the context-aware variant of ClearUser.
+func (vm *VisitManager) ClearUserCtx(ctx context.Context, userID uint32) error {
+	ctx, span := startSpan(ctx, "ClearUser", userID)
+	defer span.End()
+	start := time.Now()
+
+	if err := vm.enter(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{}, 1)
+	go func() {
+		defer vm.wg.Done()
+
+		shard := vm.shardFor(userID)
+		shard.Lock()
+		defer shard.Unlock()
+
+		vm.storage.Clear(userID)
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-done:
+		vm.metrics.observeLatency("ClearUser", start)
+		vm.metrics.recordClear()
+		vm.metrics.setUserVisits(userID, 0)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
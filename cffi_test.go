@@ -0,0 +1,168 @@
+package cffi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestManager(t *testing.T, maxVisitsPerUser int) *VisitManager {
+	t.Helper()
+	path := t.TempDir() + "/rv.dat"
+	vm, err := NewVisitManager(Options{File: path, MaxVisitsPerUser: maxVisitsPerUser, Backend: BackendGo})
+	if err != nil {
+		t.Fatalf("NewVisitManager: %v", err)
+	}
+	t.Cleanup(vm.Close)
+	return vm
+}
+
+// TestConcurrentDifferentUsers exercises AddVisit for many distinct users at
+// once. It exists to be run under `go test -race`: goStorage has no
+// per-shard protection of its own, so a race here would indicate the shared
+// state inside a Storage implementation isn't safe under VisitManager's
+// per-user locking scheme.
+func TestConcurrentDifferentUsers(t *testing.T) {
+	vm := newTestManager(t, 10)
+
+	var wg sync.WaitGroup
+	for userID := uint32(0); userID < 100; userID++ {
+		wg.Add(1)
+		go func(userID uint32) {
+			defer wg.Done()
+			for i := uint32(0); i < 10; i++ {
+				vm.AddVisit(userID, i, "https://example.com", "Example")
+			}
+		}(userID)
+	}
+	wg.Wait()
+
+	for userID := uint32(0); userID < 100; userID++ {
+		visits, err := vm.GetRecentVisits(userID)
+		if err != nil {
+			t.Fatalf("GetRecentVisits(%d): %v", userID, err)
+		}
+		if len(visits) != 10 {
+			t.Errorf("user %d: got %d visits, want 10", userID, len(visits))
+		}
+	}
+}
+
+// TestAddVisitCtxDeadline checks that AddVisitCtx respects a context that
+// expires before the underlying shard lock becomes available, returning
+// ctx.Err() rather than blocking until the lock frees up.
+func TestAddVisitCtxDeadline(t *testing.T) {
+	vm := newTestManager(t, 10)
+
+	shard := vm.shardFor(1)
+	shard.Lock()
+	defer shard.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := vm.AddVisitCtx(ctx, 1, 1, "https://example.com", "Example")
+	if err != ctx.Err() {
+		t.Fatalf("AddVisitCtx: got err %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+// TestCloseDrainsInFlight checks that Close waits for an in-flight AddVisit
+// to finish rather than closing storage out from under it, and that calls
+// made after Close fail cleanly instead of panicking.
+func TestCloseDrainsInFlight(t *testing.T) {
+	vm := newTestManager(t, 10)
+
+	shard := vm.shardFor(1)
+	shard.Lock()
+
+	addDone := make(chan struct{})
+	go func() {
+		vm.AddVisit(1, 1, "https://example.com", "Example")
+		close(addDone)
+	}()
+
+	// Give the goroutine time to reach enter() and block on the shard lock
+	// before Close starts waiting on the same waitgroup.
+	time.Sleep(20 * time.Millisecond)
+
+	closeDone := make(chan struct{})
+	go func() {
+		vm.Close()
+		close(closeDone)
+	}()
+
+	select {
+	case <-closeDone:
+		t.Fatal("Close returned before the in-flight AddVisit finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	shard.Unlock()
+	<-addDone
+	<-closeDone
+
+	if vm.AddVisit(1, 2, "https://example.com", "Example") {
+		t.Fatal("AddVisit after Close: want false")
+	}
+}
+
+// TestGoStorageRingEviction checks that goStorage evicts the oldest visit
+// once a user's ring is full, matching the cgo store's documented behavior.
+func TestGoStorageRingEviction(t *testing.T) {
+	vm := newTestManager(t, 3)
+
+	for i := uint32(1); i <= 5; i++ {
+		if !vm.AddVisit(1, i, "https://example.com", "Example") {
+			t.Fatalf("AddVisit(%d): want true", i)
+		}
+	}
+
+	visits, err := vm.GetRecentVisits(1)
+	if err != nil {
+		t.Fatalf("GetRecentVisits: %v", err)
+	}
+	if len(visits) != 3 {
+		t.Fatalf("got %d visits, want 3", len(visits))
+	}
+
+	want := []uint32{5, 4, 3}
+	for i, v := range visits {
+		if v.VisitID != want[i] {
+			t.Errorf("visits[%d].VisitID = %d, want %d", i, v.VisitID, want[i])
+		}
+	}
+}
+
+// TestGoStorageReplay checks that closing a goStorage-backed VisitManager
+// and reopening it against the same file restores the same visits,
+// exercising the log-append-and-replay path rather than the in-memory ring
+// alone.
+func TestGoStorageReplay(t *testing.T) {
+	path := t.TempDir() + "/rv.dat"
+	opts := Options{File: path, MaxVisitsPerUser: 10, Backend: BackendGo}
+
+	vm1, err := NewVisitManager(opts)
+	if err != nil {
+		t.Fatalf("NewVisitManager: %v", err)
+	}
+	vm1.AddVisit(1, 1, "https://example.com", "Example")
+	vm1.AddVisit(1, 2, "https://example.org", "Example Org")
+	vm1.DeleteVisits(1, []uint32{1})
+	vm1.Close()
+
+	vm2, err := NewVisitManager(opts)
+	if err != nil {
+		t.Fatalf("NewVisitManager (reopen): %v", err)
+	}
+	defer vm2.Close()
+
+	visits, err := vm2.GetRecentVisits(1)
+	if err != nil {
+		t.Fatalf("GetRecentVisits: %v", err)
+	}
+	if len(visits) != 1 || visits[0].VisitID != 2 {
+		t.Fatalf("got %+v, want a single visit with VisitID 2", visits)
+	}
+}
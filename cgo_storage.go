@@ -0,0 +1,118 @@
+//go:build cgo
+
+package cffi
+
+// #include "recent_visits.h"
+import "C"
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// cgoStorage is the Storage implementation backed by the C recent_visits store.
+type cgoStorage struct {
+	ptr *C.VisitManager
+}
+
+func newCgoStorage(file string, maxVisitsPerUser int) (*cgoStorage, error) {
+	cFile := C.CString(file)
+	defer C.free(unsafe.Pointer(cFile))
+
+	ptr := C.VisitManagerCreate(cFile, C.size_t(maxVisitsPerUser))
+	if ptr == nil {
+		return nil, fmt.Errorf("failed to create VisitManager")
+	}
+	return &cgoStorage{ptr: ptr}, nil
+}
+
+func (s *cgoStorage) AddVisit(userID, visitID uint32, url, text string) bool {
+	cURL := C.CString(url)
+	defer C.free(unsafe.Pointer(cURL))
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	return bool(C.VisitManagerAddVisit(s.ptr, C.uint32_t(userID), C.uint32_t(visitID), cURL, cText))
+}
+
+func (s *cgoStorage) RecentVisits(userID uint32) ([]Visit, error) {
+	visitsC, err := s.recentVisitsC(userID)
+	if err != nil || visitsC == nil {
+		return nil, err
+	}
+	return convertVisits(visitsC, 0, len(visitsC)), nil
+}
+
+// RecentVisitsPage converts only the requested window of C visits to Go,
+// rather than the whole retained set, since converting every C string to Go
+// is the expensive part of this call for large maxVisitsPerUser.
+func (s *cgoStorage) RecentVisitsPage(userID uint32, offset, limit int) ([]Visit, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	visitsC, err := s.recentVisitsC(userID)
+	if err != nil || visitsC == nil {
+		return nil, err
+	}
+	if offset >= len(visitsC) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(visitsC) {
+		end = len(visitsC)
+	}
+	return convertVisits(visitsC, offset, end), nil
+}
+
+// recentVisitsC fetches the raw, unconverted C visit pointers for userID.
+// The returned pointer is owned by the manager and must not be freed.
+func (s *cgoStorage) recentVisitsC(userID uint32) ([]*C.Visit, error) {
+	var count C.size_t
+
+	visitPtrs := C.VisitManagerGetRecentVisits(s.ptr, C.uint32_t(userID), &count)
+	if visitPtrs == nil || count == 0 {
+		return nil, nil // No visits found
+	}
+
+	// unsafe.Slice bounds the slice to count elements directly, avoiding
+	// the UB-adjacent "cast to a gigantic fixed-size array" trick for
+	// large counts.
+	return unsafe.Slice(visitPtrs, count), nil
+}
+
+// convertVisits converts visitsC[start:end] from C visits to Go visits.
+func convertVisits(visitsC []*C.Visit, start, end int) []Visit {
+	visits := make([]Visit, 0, end-start)
+	for i := start; i < end; i++ {
+		cVisit := visitsC[i]
+		visits = append(visits, Visit{
+			VisitID: uint32(cVisit.visit_id),
+			URL:     C.GoString(cVisit.url),
+			Text:    C.GoString(cVisit.text),
+			Time:    time.Unix(int64(cVisit.time.tv_sec), int64(cVisit.time.tv_nsec)),
+		})
+	}
+	return visits
+}
+
+func (s *cgoStorage) Delete(userID uint32, visitIDs []uint32) bool {
+	if len(visitIDs) == 0 {
+		return true
+	}
+
+	// Convert the visit IDs to a C array
+	cVisitIDs := (*C.uint32_t)(unsafe.Pointer(&visitIDs[0]))
+	return bool(C.VisitManagerDelete(s.ptr, C.uint32_t(userID), cVisitIDs, C.size_t(len(visitIDs))))
+}
+
+func (s *cgoStorage) Clear(userID uint32) {
+	C.VisitManagerClear(s.ptr, C.uint32_t(userID))
+}
+
+func (s *cgoStorage) Close() {
+	if s.ptr != nil {
+		C.VisitManagerFree(s.ptr)
+		s.ptr = nil
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !cgo
+
+package cffi
+
+import "fmt"
+
+// newCgoStorage is the no-cgo stand-in for cgo_storage.go's implementation.
+// It exists so that selecting BackendGo, or any other code that never
+// touches BackendCGO, builds and runs under `go test -race` and on
+// platforms without a C toolchain, without pulling cgo into the build.
+func newCgoStorage(file string, maxVisitsPerUser int) (Storage, error) {
+	return nil, fmt.Errorf("cffi: BackendCGO requires building with cgo enabled (CGO_ENABLED=1)")
+}
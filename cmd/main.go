@@ -7,7 +7,7 @@ import (
 )
 
 func main() {
-	vm, err := cffi.NewVisitManager("rv.dat", 10)
+	vm, err := cffi.NewVisitManager(cffi.Options{File: "rv.dat", MaxVisitsPerUser: 10})
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,178 @@
+// Command rvctl is an operator CLI for backing up and migrating a cffi
+// VisitManager store using VisitManager's Export/Import methods.
+package main
+
+import (
+	"cffi"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd := os.Args[1]
+	args := os.Args[2:]
+
+	var err error
+	switch cmd {
+	case "export-user":
+		err = runExportUser(args)
+	case "import-user":
+		err = runImportUser(args)
+	case "export-all":
+		err = runExportAll(args)
+	case "import-all":
+		err = runImportAll(args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "rvctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: rvctl <command> [flags]
+
+commands:
+  export-user  -file rv.dat -user <id> [-format json|pb] [-backend go|cgo]   write a user's visits to stdout
+  import-user  -file rv.dat -user <id> [-format json|pb] [-backend go|cgo]   read visits from stdin and add them for a user
+  export-all   -file rv.dat [-backend go|cgo]                                write the whole store file to stdout
+  import-all   -file rv.dat [-backend go|cgo]                                replace the store file with stdin`)
+}
+
+func parseFormat(s string) (cffi.Format, error) {
+	switch s {
+	case "json":
+		return cffi.FormatJSON, nil
+	case "pb", "protobuf":
+		return cffi.FormatProtobuf, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q (want json or pb)", s)
+	}
+}
+
+func parseBackend(s string) (cffi.Backend, error) {
+	switch s {
+	case "cgo":
+		return cffi.BackendCGO, nil
+	case "go":
+		return cffi.BackendGo, nil
+	default:
+		return 0, fmt.Errorf("unknown backend %q (want go or cgo)", s)
+	}
+}
+
+func runExportUser(args []string) error {
+	fs := flag.NewFlagSet("export-user", flag.ExitOnError)
+	file := fs.String("file", "rv.dat", "path to the store file")
+	maxVisits := fs.Int("max-visits", 1000, "max visits per user kept by the store")
+	userID := fs.Uint("user", 0, "user id to export")
+	format := fs.String("format", "json", "output format: json or pb")
+	backend := fs.String("backend", "cgo", "store backend: go or cgo")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := parseFormat(*format)
+	if err != nil {
+		return err
+	}
+	b, err := parseBackend(*backend)
+	if err != nil {
+		return err
+	}
+
+	vm, err := cffi.NewVisitManager(cffi.Options{File: *file, MaxVisitsPerUser: *maxVisits, Backend: b})
+	if err != nil {
+		return err
+	}
+	defer vm.Close()
+
+	return vm.ExportUser(uint32(*userID), os.Stdout, f)
+}
+
+func runImportUser(args []string) error {
+	fs := flag.NewFlagSet("import-user", flag.ExitOnError)
+	file := fs.String("file", "rv.dat", "path to the store file")
+	maxVisits := fs.Int("max-visits", 1000, "max visits per user kept by the store")
+	userID := fs.Uint("user", 0, "user id to import into")
+	format := fs.String("format", "json", "input format: json or pb")
+	backend := fs.String("backend", "cgo", "store backend: go or cgo")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := parseFormat(*format)
+	if err != nil {
+		return err
+	}
+	b, err := parseBackend(*backend)
+	if err != nil {
+		return err
+	}
+
+	vm, err := cffi.NewVisitManager(cffi.Options{File: *file, MaxVisitsPerUser: *maxVisits, Backend: b})
+	if err != nil {
+		return err
+	}
+	defer vm.Close()
+
+	return vm.ImportUser(uint32(*userID), os.Stdin, f)
+}
+
+func runExportAll(args []string) error {
+	fs := flag.NewFlagSet("export-all", flag.ExitOnError)
+	file := fs.String("file", "rv.dat", "path to the store file")
+	maxVisits := fs.Int("max-visits", 1000, "max visits per user kept by the store")
+	backend := fs.String("backend", "cgo", "store backend: go or cgo")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	b, err := parseBackend(*backend)
+	if err != nil {
+		return err
+	}
+
+	vm, err := cffi.NewVisitManager(cffi.Options{File: *file, MaxVisitsPerUser: *maxVisits, Backend: b})
+	if err != nil {
+		return err
+	}
+	defer vm.Close()
+
+	return vm.ExportAll(os.Stdout)
+}
+
+func runImportAll(args []string) error {
+	fs := flag.NewFlagSet("import-all", flag.ExitOnError)
+	file := fs.String("file", "rv.dat", "path to the store file")
+	maxVisits := fs.Int("max-visits", 1000, "max visits per user kept by the store")
+	backend := fs.String("backend", "cgo", "store backend: go or cgo")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	b, err := parseBackend(*backend)
+	if err != nil {
+		return err
+	}
+
+	// ImportAll requires the manager to be Closed first, so open and
+	// close it only to validate the backend before writing over the file.
+	vm, err := cffi.NewVisitManager(cffi.Options{File: *file, MaxVisitsPerUser: *maxVisits, Backend: b})
+	if err != nil {
+		return err
+	}
+	vm.Close()
+
+	return vm.ImportAll(os.Stdin)
+}
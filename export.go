@@ -0,0 +1,182 @@
+package cffi
+
+import (
+	"cffi/server/cffipb"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// Format selects the wire format used by Export/Import.
+type Format int
+
+const (
+	// FormatJSON encodes visits as newline-delimited JSON objects.
+	FormatJSON Format = iota
+	// FormatProtobuf encodes visits as a stream of length-prefixed
+	// cffipb.Visit protobuf messages.
+	FormatProtobuf
+)
+
+// ExportUser writes userID's visits to w in the given format, for backup or
+// migration independent of the on-disk store's binary layout.
+func (vm *VisitManager) ExportUser(userID uint32, w io.Writer, format Format) error {
+	visits, err := vm.GetRecentVisits(userID)
+	if err != nil {
+		return err
+	}
+	return writeVisits(w, visits, format)
+}
+
+// ImportUser reads visits from r in the given format and adds them for userID.
+// The stream is read as newest first, as ExportUser writes it, and visits are
+// added oldest first so the user's order is kept. Each visit's Time is set
+// anew by AddVisit rather than taken from the stream.
+func (vm *VisitManager) ImportUser(userID uint32, r io.Reader, format Format) error {
+	visits, err := readVisits(r, format)
+	if err != nil {
+		return err
+	}
+	for i := len(visits) - 1; i >= 0; i-- {
+		v := visits[i]
+		if !vm.AddVisit(userID, v.VisitID, v.URL, v.Text) {
+			return fmt.Errorf("cffi: import user %d: failed to add visit %d", userID, v.VisitID)
+		}
+	}
+	return nil
+}
+
+// ExportAll copies the store's entire on-disk file to w, for backup or
+// cross-host migration. It does not interpret the file's contents, so
+// restoring it with ImportAll requires the same Backend and version. Every
+// shard is locked for the duration of the copy so it captures a consistent
+// point-in-time snapshot rather than one torn by a concurrent AddVisit,
+// Delete, or Clear.
+func (vm *VisitManager) ExportAll(w io.Writer) error {
+	if err := vm.enter(); err != nil {
+		return err
+	}
+	defer vm.wg.Done()
+
+	for i := range vm.shards {
+		vm.shards[i].Lock()
+	}
+	defer func() {
+		for i := range vm.shards {
+			vm.shards[i].Unlock()
+		}
+	}()
+
+	f, err := os.Open(vm.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// ImportAll replaces the store's on-disk file with the contents of r. The
+// VisitManager must already be Closed; construct a new one afterward to
+// pick up the restored data.
+func (vm *VisitManager) ImportAll(r io.Reader) error {
+	vm.closeMu.Lock()
+	closed := vm.closed
+	vm.closeMu.Unlock()
+	if !closed {
+		return fmt.Errorf("cffi: ImportAll requires the VisitManager to be Closed first")
+	}
+
+	f, err := os.OpenFile(vm.file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func writeVisits(w io.Writer, visits []Visit, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		for _, v := range visits {
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatProtobuf:
+		for _, v := range visits {
+			b, err := proto.Marshal(visitToProto(v))
+			if err != nil {
+				return err
+			}
+			if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+				return err
+			}
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("cffi: unknown export format %v", format)
+	}
+}
+
+func readVisits(r io.Reader, format Format) ([]Visit, error) {
+	switch format {
+	case FormatJSON:
+		var visits []Visit
+		dec := json.NewDecoder(r)
+		for dec.More() {
+			var v Visit
+			if err := dec.Decode(&v); err != nil {
+				return nil, err
+			}
+			visits = append(visits, v)
+		}
+		return visits, nil
+	case FormatProtobuf:
+		var visits []Visit
+		for {
+			var n uint32
+			if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, err
+			}
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return nil, err
+			}
+			var pb cffipb.Visit
+			if err := proto.Unmarshal(buf, &pb); err != nil {
+				return nil, err
+			}
+			visits = append(visits, visitFromProto(&pb))
+		}
+		return visits, nil
+	default:
+		return nil, fmt.Errorf("cffi: unknown import format %v", format)
+	}
+}
+
+func visitToProto(v Visit) *cffipb.Visit {
+	ts, _ := ptypes.TimestampProto(v.Time)
+	return &cffipb.Visit{VisitId: v.VisitID, Url: v.URL, Text: v.Text, Time: ts}
+}
+
+func visitFromProto(pb *cffipb.Visit) Visit {
+	t, _ := ptypes.Timestamp(pb.GetTime())
+	return Visit{VisitID: pb.GetVisitId(), URL: pb.GetUrl(), Text: pb.GetText(), Time: t}
+}
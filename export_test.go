@@ -0,0 +1,108 @@
+package cffi
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestExportImportUserJSON checks that ExportUser/ImportUser round-trip a
+// user's visits through the JSON format.
+func TestExportImportUserJSON(t *testing.T) {
+	src := newTestManager(t, 10)
+	src.AddVisit(1, 1, "https://example.com", "Example")
+	src.AddVisit(1, 2, "https://example.org", "Example Org")
+
+	var buf bytes.Buffer
+	if err := src.ExportUser(1, &buf, FormatJSON); err != nil {
+		t.Fatalf("ExportUser: %v", err)
+	}
+
+	dst := newTestManager(t, 10)
+	if err := dst.ImportUser(2, &buf, FormatJSON); err != nil {
+		t.Fatalf("ImportUser: %v", err)
+	}
+
+	got, err := dst.GetRecentVisits(2)
+	if err != nil {
+		t.Fatalf("GetRecentVisits: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d visits, want 2", len(got))
+	}
+}
+
+// TestExportImportUserProtobuf is the same round trip via FormatProtobuf.
+func TestExportImportUserProtobuf(t *testing.T) {
+	src := newTestManager(t, 10)
+	src.AddVisit(1, 1, "https://example.com", "Example")
+	src.AddVisit(1, 2, "https://example.org", "Example Org")
+
+	var buf bytes.Buffer
+	if err := src.ExportUser(1, &buf, FormatProtobuf); err != nil {
+		t.Fatalf("ExportUser: %v", err)
+	}
+
+	dst := newTestManager(t, 10)
+	if err := dst.ImportUser(2, &buf, FormatProtobuf); err != nil {
+		t.Fatalf("ImportUser: %v", err)
+	}
+
+	got, err := dst.GetRecentVisits(2)
+	if err != nil {
+		t.Fatalf("GetRecentVisits: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d visits, want 2", len(got))
+	}
+}
+
+// TestExportAllImportAll checks that ExportAll/ImportAll round-trip the
+// whole on-disk store: ImportAll requires the VisitManager to be Closed
+// first, and a fresh manager opened against the restored file should see
+// the original visits.
+func TestExportAllImportAll(t *testing.T) {
+	srcPath := t.TempDir() + "/src.dat"
+	srcOpts := Options{File: srcPath, MaxVisitsPerUser: 10, Backend: BackendGo}
+
+	src, err := NewVisitManager(srcOpts)
+	if err != nil {
+		t.Fatalf("NewVisitManager (src): %v", err)
+	}
+	src.AddVisit(1, 1, "https://example.com", "Example")
+	src.AddVisit(2, 2, "https://example.org", "Example Org")
+
+	var buf bytes.Buffer
+	if err := src.ExportAll(&buf); err != nil {
+		t.Fatalf("ExportAll: %v", err)
+	}
+	src.Close()
+
+	dstPath := t.TempDir() + "/dst.dat"
+	dstOpts := Options{File: dstPath, MaxVisitsPerUser: 10, Backend: BackendGo}
+
+	dst, err := NewVisitManager(dstOpts)
+	if err != nil {
+		t.Fatalf("NewVisitManager (dst): %v", err)
+	}
+	dst.Close()
+
+	if err := dst.ImportAll(&buf); err != nil {
+		t.Fatalf("ImportAll: %v", err)
+	}
+
+	reopened, err := NewVisitManager(dstOpts)
+	if err != nil {
+		t.Fatalf("NewVisitManager (reopen): %v", err)
+	}
+	defer reopened.Close()
+
+	for userID, wantID := range map[uint32]uint32{1: 1, 2: 2} {
+		visits, err := reopened.GetRecentVisits(userID)
+		if err != nil {
+			t.Fatalf("GetRecentVisits(%d): %v", userID, err)
+		}
+		if len(visits) != 1 || visits[0].VisitID != wantID {
+			t.Fatalf("user %d: got %+v, want a single visit with VisitID %d", userID, visits, wantID)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package cffi
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// snapshotInterval is the number of logged operations between automatic
+// compactions of the on-disk log into a snapshot.
+const snapshotInterval = 1000
+
+type opKind byte
+
+const (
+	opAdd opKind = 1 + iota
+	opDelete
+	opClear
+	opSnapshot
+)
+
+// logRecord is the unit appended to the on-disk log. Only the fields
+// relevant to Kind are populated.
+type logRecord struct {
+	Kind     opKind
+	UserID   uint32
+	Visit    Visit    // used by opAdd
+	VisitIDs []uint32 // used by opDelete
+	Snapshot []Visit  // used by opSnapshot, oldest-first
+}
+
+// goStorage is a pure-Go Storage implementation backed by a bounded
+// per-user ring buffer. It requires no C toolchain, so it works on
+// platforms without cgo and under `go test -race`. Every mutation is
+// appended to an on-disk log before it is applied in memory; the log is
+// periodically compacted into a snapshot so that startup replay stays
+// bounded.
+//
+// VisitManager's shard locks only serialize operations for the same
+// userID; different users hash to different shards and can call into a
+// Storage concurrently. goStorage's users map, log file and
+// opsSinceSnapshot counter are shared across all users, so they need their
+// own mutex rather than relying on the caller's per-shard lock.
+type goStorage struct {
+	mu               sync.Mutex
+	path             string
+	maxVisitsPerUser int
+	users            map[uint32]*visitRing
+	log              *os.File
+	logEnc           *gob.Encoder // tied to log; recreated alongside it in compact
+	opsSinceSnapshot int
+}
+
+func newGoStorage(path string, maxVisitsPerUser int) (*goStorage, error) {
+	s := &goStorage{
+		path:             path,
+		maxVisitsPerUser: maxVisitsPerUser,
+		users:            make(map[uint32]*visitRing),
+	}
+
+	if err := s.replay(); err != nil {
+		return nil, fmt.Errorf("cffi: replay log %q: %w", path, err)
+	}
+
+	// Rewrite the replayed state as a fresh snapshot instead of appending to
+	// the old stream with a new gob.Encoder (see appendRecord).
+	if err := s.compact(); err != nil {
+		return nil, fmt.Errorf("cffi: open log %q: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *goStorage) replay() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := gob.NewDecoder(f)
+	for {
+		var rec logRecord
+		if err := dec.Decode(&rec); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		switch rec.Kind {
+		case opAdd:
+			s.ringFor(rec.UserID).add(rec.Visit)
+		case opDelete:
+			if r, ok := s.users[rec.UserID]; ok {
+				r.delete(rec.VisitIDs)
+			}
+		case opClear:
+			if r, ok := s.users[rec.UserID]; ok {
+				r.clear()
+			}
+		case opSnapshot:
+			r := newVisitRing(s.maxVisitsPerUser)
+			for _, v := range rec.Snapshot {
+				r.add(v)
+			}
+			s.users[rec.UserID] = r
+		}
+	}
+}
+
+func (s *goStorage) ringFor(userID uint32) *visitRing {
+	r, ok := s.users[userID]
+	if !ok {
+		r = newVisitRing(s.maxVisitsPerUser)
+		s.users[userID] = r
+	}
+	return r
+}
+
+// appendRecord encodes rec onto the log's single long-lived gob.Encoder.
+// gob writes a type descriptor the first time it sees a given type on a
+// stream and omits it afterward, so the decoder on the read side expects
+// one continuous stream from one encoder; encoding each record with a
+// fresh gob.Encoder would resend the descriptor every time and the
+// decoder would reject it as a duplicate.
+func (s *goStorage) appendRecord(rec logRecord) error {
+	if err := s.logEnc.Encode(rec); err != nil {
+		return err
+	}
+	s.opsSinceSnapshot++
+	if s.opsSinceSnapshot >= snapshotInterval {
+		return s.compact()
+	}
+	return nil
+}
+
+// compact rewrites the log as one opSnapshot record per user holding their
+// current ring contents, dropping the history that produced that state.
+func (s *goStorage) compact() error {
+	tmpPath := s.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	enc := gob.NewEncoder(f)
+	for userID, r := range s.users {
+		rec := logRecord{Kind: opSnapshot, UserID: userID, Snapshot: r.oldestFirst()}
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		f.Close()
+		return err
+	}
+
+	// f becomes the log and enc stays its encoder, so the file remains one
+	// gob stream (see appendRecord).
+	if s.log != nil {
+		s.log.Close()
+	}
+	s.log = f
+	s.logEnc = enc
+	s.opsSinceSnapshot = 0
+	return nil
+}
+
+func (s *goStorage) AddVisit(userID, visitID uint32, url, text string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := Visit{VisitID: visitID, URL: url, Text: text, Time: time.Now()}
+	if err := s.appendRecord(logRecord{Kind: opAdd, UserID: userID, Visit: v}); err != nil {
+		return false
+	}
+	s.ringFor(userID).add(v)
+	return true
+}
+
+func (s *goStorage) RecentVisits(userID uint32) ([]Visit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return r.newestFirst(), nil
+}
+
+func (s *goStorage) RecentVisitsPage(userID uint32, offset, limit int) ([]Visit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return r.page(offset, limit), nil
+}
+
+func (s *goStorage) Delete(userID uint32, visitIDs []uint32) bool {
+	if len(visitIDs) == 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendRecord(logRecord{Kind: opDelete, UserID: userID, VisitIDs: visitIDs}); err != nil {
+		return false
+	}
+	if r, ok := s.users[userID]; ok {
+		r.delete(visitIDs)
+	}
+	return true
+}
+
+func (s *goStorage) Clear(userID uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendRecord(logRecord{Kind: opClear, UserID: userID}); err != nil {
+		return
+	}
+	if r, ok := s.users[userID]; ok {
+		r.clear()
+	}
+}
+
+func (s *goStorage) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.log != nil {
+		s.log.Close()
+		s.log = nil
+		s.logEnc = nil
+	}
+}
+
+// visitRing is a fixed-capacity FIFO of visits for a single user. Once full,
+// adding a visit evicts the oldest one, matching the eviction behavior of
+// the cgo-backed store.
+type visitRing struct {
+	visits []Visit
+	cap    int
+	start  int
+	count  int
+}
+
+func newVisitRing(capacity int) *visitRing {
+	return &visitRing{visits: make([]Visit, capacity), cap: capacity}
+}
+
+func (r *visitRing) add(v Visit) {
+	idx := (r.start + r.count) % r.cap
+	if r.count == r.cap {
+		r.visits[r.start] = v
+		r.start = (r.start + 1) % r.cap
+		return
+	}
+	r.visits[idx] = v
+	r.count++
+}
+
+// newestFirst returns the ring's visits ordered newest-first, matching the
+// order GetRecentVisits has always returned.
+func (r *visitRing) newestFirst() []Visit {
+	out := make([]Visit, r.count)
+	for i := 0; i < r.count; i++ {
+		idx := (r.start + r.count - 1 - i) % r.cap
+		out[i] = r.visits[idx]
+	}
+	return out
+}
+
+// page returns up to limit visits starting at the given 0-based offset into
+// the newest-first ordering, computing indices directly into the ring
+// rather than materializing the full newestFirst slice first.
+func (r *visitRing) page(offset, limit int) []Visit {
+	if offset >= r.count || limit <= 0 {
+		return nil
+	}
+	end := offset + limit
+	if end > r.count {
+		end = r.count
+	}
+
+	out := make([]Visit, end-offset)
+	for i := offset; i < end; i++ {
+		idx := (r.start + r.count - 1 - i) % r.cap
+		out[i-offset] = r.visits[idx]
+	}
+	return out
+}
+
+// oldestFirst returns the ring's visits in insertion order, used to encode
+// a snapshot that replays back into the same state.
+func (r *visitRing) oldestFirst() []Visit {
+	out := make([]Visit, r.count)
+	for i := 0; i < r.count; i++ {
+		idx := (r.start + i) % r.cap
+		out[i] = r.visits[idx]
+	}
+	return out
+}
+
+func (r *visitRing) delete(visitIDs []uint32) {
+	if r.count == 0 {
+		return
+	}
+	remove := make(map[uint32]bool, len(visitIDs))
+	for _, id := range visitIDs {
+		remove[id] = true
+	}
+
+	kept := make([]Visit, 0, r.count)
+	for i := 0; i < r.count; i++ {
+		idx := (r.start + i) % r.cap
+		if v := r.visits[idx]; !remove[v.VisitID] {
+			kept = append(kept, v)
+		}
+	}
+	r.start = 0
+	r.count = copy(r.visits, kept)
+}
+
+func (r *visitRing) clear() {
+	r.start = 0
+	r.count = 0
+}
@@ -0,0 +1,75 @@
+package cffi
+
+// iteratorBatchSize is how many visits IterateVisits fetches per call to
+// GetRecentVisitsPage. Fetching in batches, rather than the whole result
+// set up front, lets a caller stop early without paying for visits it never
+// looks at, and keeps the shard lock held only while a batch is fetched.
+const iteratorBatchSize = 64
+
+// VisitIterator streams a user's recent visits, newest first, without
+// materializing the full result set up front. The zero value is not usable;
+// obtain one with VisitManager.IterateVisits.
+type VisitIterator struct {
+	vm     *VisitManager
+	userID uint32
+
+	offset int
+	batch  []Visit
+	pos    int
+
+	current Visit
+	done    bool
+	err     error
+}
+
+// IterateVisits returns an iterator over userID's recent visits. The
+// iterator must be closed with Close once the caller is done with it, even
+// if iteration stopped before Next returned false.
+func (vm *VisitManager) IterateVisits(userID uint32) *VisitIterator {
+	return &VisitIterator{vm: vm, userID: userID}
+}
+
+// Next advances the iterator and reports whether a visit is available.
+// It returns false once visits are exhausted or an error occurred; use Err
+// to distinguish the two.
+func (it *VisitIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+
+	if it.pos >= len(it.batch) {
+		batch, err := it.vm.GetRecentVisitsPage(it.userID, it.offset, iteratorBatchSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if len(batch) == 0 {
+			it.done = true
+			return false
+		}
+		it.batch = batch
+		it.offset += len(batch)
+		it.pos = 0
+	}
+
+	it.current = it.batch[it.pos]
+	it.pos++
+	return true
+}
+
+// Visit returns the visit most recently yielded by Next.
+func (it *VisitIterator) Visit() Visit {
+	return it.current
+}
+
+// Err returns the error, if any, that caused Next to stop iteration early.
+func (it *VisitIterator) Err() error {
+	return it.err
+}
+
+// Close releases resources held by the iterator. It is always safe to call
+// and currently always returns nil; it exists so callers can treat
+// VisitIterator like other Closers.
+func (it *VisitIterator) Close() error {
+	return nil
+}
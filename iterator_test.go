@@ -0,0 +1,88 @@
+package cffi
+
+import "testing"
+
+// TestGetRecentVisitsPage checks offset/limit windowing against a user with
+// more visits than a single page, including the tail page that's shorter
+// than limit and the empty page once offset runs past the end.
+func TestGetRecentVisitsPage(t *testing.T) {
+	vm := newTestManager(t, 10)
+	for i := uint32(1); i <= 10; i++ {
+		vm.AddVisit(1, i, "https://example.com", "Example")
+	}
+
+	page, err := vm.GetRecentVisitsPage(1, 0, 4)
+	if err != nil {
+		t.Fatalf("GetRecentVisitsPage(0, 4): %v", err)
+	}
+	want := []uint32{10, 9, 8, 7}
+	if len(page) != len(want) {
+		t.Fatalf("got %d visits, want %d", len(page), len(want))
+	}
+	for i, v := range page {
+		if v.VisitID != want[i] {
+			t.Errorf("page[%d].VisitID = %d, want %d", i, v.VisitID, want[i])
+		}
+	}
+
+	page, err = vm.GetRecentVisitsPage(1, 8, 4)
+	if err != nil {
+		t.Fatalf("GetRecentVisitsPage(8, 4): %v", err)
+	}
+	if len(page) != 2 || page[0].VisitID != 2 || page[1].VisitID != 1 {
+		t.Fatalf("tail page = %+v, want [VisitID 2, VisitID 1]", page)
+	}
+
+	page, err = vm.GetRecentVisitsPage(1, 10, 4)
+	if err != nil {
+		t.Fatalf("GetRecentVisitsPage(10, 4): %v", err)
+	}
+	if len(page) != 0 {
+		t.Fatalf("past-the-end page = %+v, want empty", page)
+	}
+}
+
+// TestIterateVisits checks that the iterator yields every visit, newest
+// first, across multiple underlying page fetches.
+func TestIterateVisits(t *testing.T) {
+	vm := newTestManager(t, 10)
+	for i := uint32(1); i <= 10; i++ {
+		vm.AddVisit(1, i, "https://example.com", "Example")
+	}
+
+	it := vm.IterateVisits(1)
+	defer it.Close()
+
+	var gotIDs []uint32
+	for it.Next() {
+		gotIDs = append(gotIDs, it.Visit().VisitID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration error: %v", err)
+	}
+
+	if len(gotIDs) != 10 {
+		t.Fatalf("got %d visits, want 10", len(gotIDs))
+	}
+	for i, id := range gotIDs {
+		if want := uint32(10 - i); id != want {
+			t.Errorf("gotIDs[%d] = %d, want %d", i, id, want)
+		}
+	}
+}
+
+// TestIterateVisitsEmpty checks that iterating a user with no visits stops
+// immediately without error.
+func TestIterateVisitsEmpty(t *testing.T) {
+	vm := newTestManager(t, 10)
+
+	it := vm.IterateVisits(1)
+	defer it.Close()
+
+	if it.Next() {
+		t.Fatal("Next() on an empty user: want false")
+	}
+	if it.Err() != nil {
+		t.Fatalf("Err() on an empty user: %v, want nil", it.Err())
+	}
+}
@@ -0,0 +1,96 @@
+package cffi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics holds the optional Prometheus instrumentation for a VisitManager,
+// enabled via WithMetrics. A nil *metrics (the default) means metrics are
+// disabled; every method on it is a safe no-op in that case.
+type metrics struct {
+	visitsAdded   prometheus.Counter
+	visitsDeleted prometheus.Counter
+	visitsCleared prometheus.Counter
+	callLatency   *prometheus.HistogramVec
+	userVisits    *prometheus.GaugeVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		visitsAdded: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "visits_added_total",
+			Help: "Total number of visits added.",
+		}),
+		visitsDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "visits_deleted_total",
+			Help: "Total number of visit IDs passed to successful DeleteVisits calls.",
+		}),
+		visitsCleared: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "visits_cleared_total",
+			Help: "Total number of ClearUser calls.",
+		}),
+		callLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "visit_manager_call_duration_seconds",
+			Help: "Latency of VisitManager storage calls, by method.",
+		}, []string{"method"}),
+		userVisits: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "visit_manager_user_visits",
+			Help: "Number of visits currently retained, by user. Cardinality " +
+				"grows with the number of distinct users ever seen and never " +
+				"shrinks; only enable this with WithMetrics on deployments with " +
+				"a bounded, known-small user_id space.",
+		}, []string{"user_id"}),
+	}
+
+	reg.MustRegister(m.visitsAdded, m.visitsDeleted, m.visitsCleared, m.callLatency, m.userVisits)
+	return m
+}
+
+func (m *metrics) observeLatency(method string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.callLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+func (m *metrics) recordAdd() {
+	if m == nil {
+		return
+	}
+	m.visitsAdded.Inc()
+}
+
+func (m *metrics) recordDelete(n int) {
+	if m == nil {
+		return
+	}
+	m.visitsDeleted.Add(float64(n))
+}
+
+func (m *metrics) recordClear() {
+	if m == nil {
+		return
+	}
+	m.visitsCleared.Inc()
+}
+
+// setUserVisits records the current retained-visit count for userID.
+//
+// Known limitation: userVisits carries one time series per distinct
+// user_id ever passed here, and Prometheus has no way to expire a label
+// combination it no longer sees, so this series set only grows. That is
+// fine for a deployment with a small, roughly fixed user population; for
+// a large or unbounded one (e.g. public signups), enabling WithMetrics
+// will eventually exhaust the scraping Prometheus's memory. Scoping this
+// gauge to avoid raw user IDs (e.g. dropping it, or replacing it with an
+// aggregate like total visits across all users) is tracked as future
+// work rather than done here, since the gauge was requested per-user.
+func (m *metrics) setUserVisits(userID uint32, count int) {
+	if m == nil {
+		return
+	}
+	m.userVisits.WithLabelValues(strconv.FormatUint(uint64(userID), 10)).Set(float64(count))
+}
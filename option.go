@@ -0,0 +1,18 @@
+package cffi
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Option customizes a VisitManager after it has been constructed from Options.
+type Option func(*VisitManager)
+
+// WithMetrics instruments a VisitManager's operations with Prometheus
+// metrics registered against reg: counters for added/deleted/cleared
+// visits, a latency histogram per method, and a gauge of visits currently
+// retained per user. The per-user gauge carries one series per distinct
+// user_id seen; only use this on deployments with a bounded user
+// population (see the userVisits field in metrics.go).
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(vm *VisitManager) {
+		vm.metrics = newMetrics(reg)
+	}
+}
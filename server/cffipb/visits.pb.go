@@ -0,0 +1,220 @@
+// Package cffipb holds the message and service types described by
+// server/visits.proto. These are hand-maintained, not protoc output: keep
+// them in sync with the .proto file by hand until `protoc`/`protoc-gen-go`
+// are wired into the build.
+package cffipb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+)
+
+type Visit struct {
+	VisitId uint32               `protobuf:"varint,1,opt,name=visit_id,json=visitId,proto3" json:"visit_id,omitempty"`
+	Url     string               `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Text    string               `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Time    *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (m *Visit) Reset()         { *m = Visit{} }
+func (m *Visit) String() string { return proto.CompactTextString(m) }
+func (*Visit) ProtoMessage()    {}
+
+func (m *Visit) GetVisitId() uint32 {
+	if m != nil {
+		return m.VisitId
+	}
+	return 0
+}
+
+func (m *Visit) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Visit) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+func (m *Visit) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+type AddVisitRequest struct {
+	UserId  uint32 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	VisitId uint32 `protobuf:"varint,2,opt,name=visit_id,json=visitId,proto3" json:"visit_id,omitempty"`
+	Url     string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Text    string `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (m *AddVisitRequest) Reset()         { *m = AddVisitRequest{} }
+func (m *AddVisitRequest) String() string { return proto.CompactTextString(m) }
+func (*AddVisitRequest) ProtoMessage()    {}
+
+func (m *AddVisitRequest) GetUserId() uint32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *AddVisitRequest) GetVisitId() uint32 {
+	if m != nil {
+		return m.VisitId
+	}
+	return 0
+}
+
+func (m *AddVisitRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *AddVisitRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+type AddVisitResponse struct {
+	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+}
+
+func (m *AddVisitResponse) Reset()         { *m = AddVisitResponse{} }
+func (m *AddVisitResponse) String() string { return proto.CompactTextString(m) }
+func (*AddVisitResponse) ProtoMessage()    {}
+
+func (m *AddVisitResponse) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+type GetRecentVisitsRequest struct {
+	UserId uint32 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (m *GetRecentVisitsRequest) Reset()         { *m = GetRecentVisitsRequest{} }
+func (m *GetRecentVisitsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRecentVisitsRequest) ProtoMessage()    {}
+
+func (m *GetRecentVisitsRequest) GetUserId() uint32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type GetRecentVisitsResponse struct {
+	Visits []*Visit `protobuf:"bytes,1,rep,name=visits,proto3" json:"visits,omitempty"`
+}
+
+func (m *GetRecentVisitsResponse) Reset()         { *m = GetRecentVisitsResponse{} }
+func (m *GetRecentVisitsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRecentVisitsResponse) ProtoMessage()    {}
+
+func (m *GetRecentVisitsResponse) GetVisits() []*Visit {
+	if m != nil {
+		return m.Visits
+	}
+	return nil
+}
+
+type DeleteVisitsRequest struct {
+	UserId   uint32   `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	VisitIds []uint32 `protobuf:"varint,2,rep,packed,name=visit_ids,json=visitIds,proto3" json:"visit_ids,omitempty"`
+}
+
+func (m *DeleteVisitsRequest) Reset()         { *m = DeleteVisitsRequest{} }
+func (m *DeleteVisitsRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteVisitsRequest) ProtoMessage()    {}
+
+func (m *DeleteVisitsRequest) GetUserId() uint32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *DeleteVisitsRequest) GetVisitIds() []uint32 {
+	if m != nil {
+		return m.VisitIds
+	}
+	return nil
+}
+
+type DeleteVisitsResponse struct {
+	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+}
+
+func (m *DeleteVisitsResponse) Reset()         { *m = DeleteVisitsResponse{} }
+func (m *DeleteVisitsResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteVisitsResponse) ProtoMessage()    {}
+
+func (m *DeleteVisitsResponse) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+type ClearUserRequest struct {
+	UserId uint32 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (m *ClearUserRequest) Reset()         { *m = ClearUserRequest{} }
+func (m *ClearUserRequest) String() string { return proto.CompactTextString(m) }
+func (*ClearUserRequest) ProtoMessage()    {}
+
+func (m *ClearUserRequest) GetUserId() uint32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type ClearUserResponse struct{}
+
+func (m *ClearUserResponse) Reset()         { *m = ClearUserResponse{} }
+func (m *ClearUserResponse) String() string { return proto.CompactTextString(m) }
+func (*ClearUserResponse) ProtoMessage()    {}
+
+type WatchUserRequest struct {
+	UserId uint32 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+func (m *WatchUserRequest) Reset()         { *m = WatchUserRequest{} }
+func (m *WatchUserRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchUserRequest) ProtoMessage()    {}
+
+func (m *WatchUserRequest) GetUserId() uint32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Visit)(nil), "cffi.Visit")
+	proto.RegisterType((*AddVisitRequest)(nil), "cffi.AddVisitRequest")
+	proto.RegisterType((*AddVisitResponse)(nil), "cffi.AddVisitResponse")
+	proto.RegisterType((*GetRecentVisitsRequest)(nil), "cffi.GetRecentVisitsRequest")
+	proto.RegisterType((*GetRecentVisitsResponse)(nil), "cffi.GetRecentVisitsResponse")
+	proto.RegisterType((*DeleteVisitsRequest)(nil), "cffi.DeleteVisitsRequest")
+	proto.RegisterType((*DeleteVisitsResponse)(nil), "cffi.DeleteVisitsResponse")
+	proto.RegisterType((*ClearUserRequest)(nil), "cffi.ClearUserRequest")
+	proto.RegisterType((*ClearUserResponse)(nil), "cffi.ClearUserResponse")
+	proto.RegisterType((*WatchUserRequest)(nil), "cffi.WatchUserRequest")
+}
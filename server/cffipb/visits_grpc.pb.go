@@ -0,0 +1,236 @@
+// This file holds the gRPC client/server types for VisitService, described
+// in server/visits.proto. It is hand-maintained, not protoc-gen-go-grpc
+// output: keep it in sync with the .proto file by hand until `protoc` is
+// wired into the build.
+
+package cffipb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+const (
+	VisitService_AddVisit_FullMethodName        = "/cffi.VisitService/AddVisit"
+	VisitService_GetRecentVisits_FullMethodName = "/cffi.VisitService/GetRecentVisits"
+	VisitService_DeleteVisits_FullMethodName    = "/cffi.VisitService/DeleteVisits"
+	VisitService_ClearUser_FullMethodName       = "/cffi.VisitService/ClearUser"
+	VisitService_WatchUser_FullMethodName       = "/cffi.VisitService/WatchUser"
+)
+
+// VisitServiceClient is the client API for VisitService.
+type VisitServiceClient interface {
+	AddVisit(ctx context.Context, in *AddVisitRequest, opts ...grpc.CallOption) (*AddVisitResponse, error)
+	GetRecentVisits(ctx context.Context, in *GetRecentVisitsRequest, opts ...grpc.CallOption) (*GetRecentVisitsResponse, error)
+	DeleteVisits(ctx context.Context, in *DeleteVisitsRequest, opts ...grpc.CallOption) (*DeleteVisitsResponse, error)
+	ClearUser(ctx context.Context, in *ClearUserRequest, opts ...grpc.CallOption) (*ClearUserResponse, error)
+	WatchUser(ctx context.Context, in *WatchUserRequest, opts ...grpc.CallOption) (VisitService_WatchUserClient, error)
+}
+
+type visitServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVisitServiceClient(cc grpc.ClientConnInterface) VisitServiceClient {
+	return &visitServiceClient{cc}
+}
+
+func (c *visitServiceClient) AddVisit(ctx context.Context, in *AddVisitRequest, opts ...grpc.CallOption) (*AddVisitResponse, error) {
+	out := new(AddVisitResponse)
+	if err := c.cc.Invoke(ctx, VisitService_AddVisit_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *visitServiceClient) GetRecentVisits(ctx context.Context, in *GetRecentVisitsRequest, opts ...grpc.CallOption) (*GetRecentVisitsResponse, error) {
+	out := new(GetRecentVisitsResponse)
+	if err := c.cc.Invoke(ctx, VisitService_GetRecentVisits_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *visitServiceClient) DeleteVisits(ctx context.Context, in *DeleteVisitsRequest, opts ...grpc.CallOption) (*DeleteVisitsResponse, error) {
+	out := new(DeleteVisitsResponse)
+	if err := c.cc.Invoke(ctx, VisitService_DeleteVisits_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *visitServiceClient) ClearUser(ctx context.Context, in *ClearUserRequest, opts ...grpc.CallOption) (*ClearUserResponse, error) {
+	out := new(ClearUserResponse)
+	if err := c.cc.Invoke(ctx, VisitService_ClearUser_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *visitServiceClient) WatchUser(ctx context.Context, in *WatchUserRequest, opts ...grpc.CallOption) (VisitService_WatchUserClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VisitService_ServiceDesc.Streams[0], VisitService_WatchUser_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &visitServiceWatchUserClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type VisitService_WatchUserClient interface {
+	Recv() (*Visit, error)
+	grpc.ClientStream
+}
+
+type visitServiceWatchUserClient struct {
+	grpc.ClientStream
+}
+
+func (x *visitServiceWatchUserClient) Recv() (*Visit, error) {
+	m := new(Visit)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// VisitServiceServer is the server API for VisitService.
+type VisitServiceServer interface {
+	AddVisit(context.Context, *AddVisitRequest) (*AddVisitResponse, error)
+	GetRecentVisits(context.Context, *GetRecentVisitsRequest) (*GetRecentVisitsResponse, error)
+	DeleteVisits(context.Context, *DeleteVisitsRequest) (*DeleteVisitsResponse, error)
+	ClearUser(context.Context, *ClearUserRequest) (*ClearUserResponse, error)
+	WatchUser(*WatchUserRequest, VisitService_WatchUserServer) error
+}
+
+// UnimplementedVisitServiceServer must be embedded for forward compatibility.
+type UnimplementedVisitServiceServer struct{}
+
+func (UnimplementedVisitServiceServer) AddVisit(context.Context, *AddVisitRequest) (*AddVisitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddVisit not implemented")
+}
+func (UnimplementedVisitServiceServer) GetRecentVisits(context.Context, *GetRecentVisitsRequest) (*GetRecentVisitsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRecentVisits not implemented")
+}
+func (UnimplementedVisitServiceServer) DeleteVisits(context.Context, *DeleteVisitsRequest) (*DeleteVisitsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteVisits not implemented")
+}
+func (UnimplementedVisitServiceServer) ClearUser(context.Context, *ClearUserRequest) (*ClearUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearUser not implemented")
+}
+func (UnimplementedVisitServiceServer) WatchUser(*WatchUserRequest, VisitService_WatchUserServer) error {
+	return status.Error(codes.Unimplemented, "method WatchUser not implemented")
+}
+
+func RegisterVisitServiceServer(s grpc.ServiceRegistrar, srv VisitServiceServer) {
+	s.RegisterService(&VisitService_ServiceDesc, srv)
+}
+
+func _VisitService_AddVisit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddVisitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VisitServiceServer).AddVisit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: VisitService_AddVisit_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VisitServiceServer).AddVisit(ctx, req.(*AddVisitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VisitService_GetRecentVisits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecentVisitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VisitServiceServer).GetRecentVisits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: VisitService_GetRecentVisits_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VisitServiceServer).GetRecentVisits(ctx, req.(*GetRecentVisitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VisitService_DeleteVisits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVisitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VisitServiceServer).DeleteVisits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: VisitService_DeleteVisits_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VisitServiceServer).DeleteVisits(ctx, req.(*DeleteVisitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VisitService_ClearUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VisitServiceServer).ClearUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: VisitService_ClearUser_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VisitServiceServer).ClearUser(ctx, req.(*ClearUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VisitService_WatchUser_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUserRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VisitServiceServer).WatchUser(m, &visitServiceWatchUserServer{stream})
+}
+
+type VisitService_WatchUserServer interface {
+	Send(*Visit) error
+	grpc.ServerStream
+}
+
+type visitServiceWatchUserServer struct {
+	grpc.ServerStream
+}
+
+func (x *visitServiceWatchUserServer) Send(m *Visit) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// VisitService_ServiceDesc is the grpc.ServiceDesc for VisitService.
+var VisitService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cffi.VisitService",
+	HandlerType: (*VisitServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "AddVisit", Handler: _VisitService_AddVisit_Handler},
+		{MethodName: "GetRecentVisits", Handler: _VisitService_GetRecentVisits_Handler},
+		{MethodName: "DeleteVisits", Handler: _VisitService_DeleteVisits_Handler},
+		{MethodName: "ClearUser", Handler: _VisitService_ClearUser_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUser",
+			Handler:       _VisitService_WatchUser_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "server/visits.proto",
+}
@@ -0,0 +1,135 @@
+// Package client is a small Go client for the cffi HTTP API, letting callers
+// read and write recent visits without linking cgo.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Visit mirrors cffi.Visit's JSON form, so this package need not import
+// cffi (and with it cgo).
+type Visit struct {
+	VisitID uint32
+	URL     string
+	Text    string
+	Time    time.Time
+}
+
+// Client talks to a cffi server.Server over HTTP+JSON.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// New returns a Client that sends requests to baseURL, e.g. "http://localhost:8080".
+func New(baseURL string) *Client {
+	return &Client{baseURL: baseURL, http: http.DefaultClient}
+}
+
+// AddVisit adds a visit for userID.
+func (c *Client) AddVisit(userID, visitID uint32, url, text string) (bool, error) {
+	body, err := json.Marshal(addVisitBody{VisitID: visitID, URL: url, Text: text})
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.http.Post(c.url(userID, ""), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("cffi: add visit: unexpected status %d", resp.StatusCode)
+	}
+
+	var out okResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return false, err
+	}
+	return out.OK, nil
+}
+
+// GetRecentVisits returns the recent visits for userID.
+func (c *Client) GetRecentVisits(userID uint32) ([]Visit, error) {
+	resp, err := c.http.Get(c.url(userID, ""))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cffi: get recent visits: unexpected status %d", resp.StatusCode)
+	}
+
+	var visits []Visit
+	if err := json.NewDecoder(resp.Body).Decode(&visits); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+// DeleteVisits deletes the given visit IDs for userID.
+func (c *Client) DeleteVisits(userID uint32, visitIDs []uint32) (bool, error) {
+	body, err := json.Marshal(visitIDs)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, c.url(userID, ""), bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("cffi: delete visits: unexpected status %d", resp.StatusCode)
+	}
+
+	var out okResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return false, err
+	}
+	return out.OK, nil
+}
+
+// ClearUser removes all visits for userID.
+func (c *Client) ClearUser(userID uint32) error {
+	req, err := http.NewRequest(http.MethodDelete, c.url(userID, "all"), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("cffi: clear user: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *Client) url(userID uint32, suffix string) string {
+	u := c.baseURL + "/visits/" + strconv.FormatUint(uint64(userID), 10)
+	if suffix != "" {
+		u += "/" + suffix
+	}
+	return u
+}
+
+type addVisitBody struct {
+	VisitID uint32 `json:"visit_id"`
+	URL     string `json:"url"`
+	Text    string `json:"text"`
+}
+
+type okResponse struct {
+	OK bool `json:"ok"`
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"cffi"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HTTPHandler returns an http.Handler mirroring the gRPC VisitService:
+//
+//	POST   /visits/{userID}        add a visit (JSON body: visit_id, url, text)
+//	GET    /visits/{userID}        recent visits for the user
+//	DELETE /visits/{userID}        delete visit ids (JSON body: array of ids)
+//	DELETE /visits/{userID}/all    clear all visits for the user
+//	GET    /visits/{userID}/watch  stream new visits as server-sent events
+func (s *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/visits/", s.handleVisits)
+	return mux
+}
+
+type addVisitBody struct {
+	VisitID uint32 `json:"visit_id"`
+	URL     string `json:"url"`
+	Text    string `json:"text"`
+}
+
+func (s *Server) handleVisits(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/visits/"), "/")
+	userID64, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil || parts[0] == "" {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+	userID := uint32(userID64)
+
+	switch {
+	case len(parts) == 2 && parts[1] == "watch" && r.Method == http.MethodGet:
+		s.handleWatch(w, r, userID)
+	case len(parts) == 2 && parts[1] == "all" && r.Method == http.MethodDelete:
+		s.handleClear(w, r, userID)
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		s.handleAdd(w, r, userID)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.handleRecent(w, r, userID)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		s.handleDelete(w, r, userID)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request, userID uint32) {
+	var body addVisitBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ok, err := s.vm.AddVisitCtx(r.Context(), userID, body.VisitID, body.URL, body.Text)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestTimeout)
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": ok})
+}
+
+func (s *Server) handleRecent(w http.ResponseWriter, r *http.Request, userID uint32) {
+	visits, err := s.vm.GetRecentVisitsCtx(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestTimeout)
+		return
+	}
+	writeJSON(w, visits)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request, userID uint32) {
+	var visitIDs []uint32
+	if err := json.NewDecoder(r.Body).Decode(&visitIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ok, err := s.vm.DeleteVisitsCtx(r.Context(), userID, visitIDs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestTimeout)
+		return
+	}
+	writeJSON(w, map[string]bool{"ok": ok})
+}
+
+func (s *Server) handleClear(w http.ResponseWriter, r *http.Request, userID uint32) {
+	if err := s.vm.ClearUserCtx(r.Context(), userID); err != nil {
+		http.Error(w, err.Error(), http.StatusRequestTimeout)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request, userID uint32) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	err := watchUser(r.Context(), s.vm, userID, func(v cffi.Visit) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(append([]byte("data: "), b...), '\n', '\n')); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		// watchUser stopped for a reason other than the request being
+		// canceled (a failed write, a deadline, or a VisitManager error);
+		// the stream ends without writing anything further.
+		return
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bytes"
+	"cffi"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	vm, err := cffi.NewVisitManager(cffi.Options{
+		File:             t.TempDir() + "/rv.dat",
+		MaxVisitsPerUser: 10,
+		Backend:          cffi.BackendGo,
+	})
+	if err != nil {
+		t.Fatalf("NewVisitManager: %v", err)
+	}
+	t.Cleanup(vm.Close)
+	return New(vm)
+}
+
+// TestHTTPAddAndGetRecent exercises the HTTP add/get-recent round trip,
+// checking that the handler plumbs the JSON body through to the
+// VisitManager and reports the added visit back.
+func TestHTTPAddAndGetRecent(t *testing.T) {
+	s := newTestServer(t)
+	h := s.HTTPHandler()
+
+	body, _ := json.Marshal(addVisitBody{VisitID: 1, URL: "https://example.com", Text: "Example"})
+	req := httptest.NewRequest(http.MethodPost, "/visits/42", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /visits/42: status %d, body %q", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/visits/42", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /visits/42: status %d, body %q", rec.Code, rec.Body.String())
+	}
+
+	var visits []cffi.Visit
+	if err := json.Unmarshal(rec.Body.Bytes(), &visits); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(visits) != 1 || visits[0].VisitID != 1 {
+		t.Fatalf("got %+v, want a single visit with VisitID 1", visits)
+	}
+}
+
+// TestHTTPInvalidUserID checks that a non-numeric user id in the path is
+// rejected with 400 rather than panicking on the ParseUint failure.
+func TestHTTPInvalidUserID(t *testing.T) {
+	s := newTestServer(t)
+	h := s.HTTPHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/visits/not-a-number", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("GET /visits/not-a-number: status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// TestHTTPDeleteAndClear checks that delete-by-id and clear-all both reach
+// the underlying VisitManager.
+func TestHTTPDeleteAndClear(t *testing.T) {
+	s := newTestServer(t)
+	h := s.HTTPHandler()
+
+	for _, id := range []uint32{1, 2} {
+		body, _ := json.Marshal(addVisitBody{VisitID: id, URL: "https://example.com", Text: "Example"})
+		req := httptest.NewRequest(http.MethodPost, "/visits/7", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("POST /visits/7: status %d", rec.Code)
+		}
+	}
+
+	ids, _ := json.Marshal([]uint32{1})
+	req := httptest.NewRequest(http.MethodDelete, "/visits/7", bytes.NewReader(ids))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DELETE /visits/7: status %d, body %q", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/visits/7/all", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE /visits/7/all: status %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/visits/7", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var visits []cffi.Visit
+	if err := json.Unmarshal(rec.Body.Bytes(), &visits); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(visits) != 0 {
+		t.Fatalf("got %d visits after clear, want 0", len(visits))
+	}
+}
@@ -0,0 +1,82 @@
+// Package server exposes a cffi.VisitManager over HTTP+JSON and gRPC so
+// other services can read and write recent visits without linking cgo. See
+// visits.proto for the gRPC contract.
+package server
+
+import (
+	"cffi"
+	"cffi/server/cffipb"
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+)
+
+// Server wraps a VisitManager and implements cffipb.VisitServiceServer plus
+// an equivalent HTTP+JSON API via HTTPHandler.
+type Server struct {
+	cffipb.UnimplementedVisitServiceServer
+
+	vm *cffi.VisitManager
+}
+
+// New returns a Server backed by vm. The caller remains responsible for
+// closing vm.
+func New(vm *cffi.VisitManager) *Server {
+	return &Server{vm: vm}
+}
+
+// AddVisit implements cffipb.VisitServiceServer.
+func (s *Server) AddVisit(ctx context.Context, req *cffipb.AddVisitRequest) (*cffipb.AddVisitResponse, error) {
+	ok, err := s.vm.AddVisitCtx(ctx, req.GetUserId(), req.GetVisitId(), req.GetUrl(), req.GetText())
+	if err != nil {
+		return nil, err
+	}
+	return &cffipb.AddVisitResponse{Ok: ok}, nil
+}
+
+// GetRecentVisits implements cffipb.VisitServiceServer.
+func (s *Server) GetRecentVisits(ctx context.Context, req *cffipb.GetRecentVisitsRequest) (*cffipb.GetRecentVisitsResponse, error) {
+	visits, err := s.vm.GetRecentVisitsCtx(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return &cffipb.GetRecentVisitsResponse{Visits: toPBVisits(visits)}, nil
+}
+
+// DeleteVisits implements cffipb.VisitServiceServer.
+func (s *Server) DeleteVisits(ctx context.Context, req *cffipb.DeleteVisitsRequest) (*cffipb.DeleteVisitsResponse, error) {
+	ok, err := s.vm.DeleteVisitsCtx(ctx, req.GetUserId(), req.GetVisitIds())
+	if err != nil {
+		return nil, err
+	}
+	return &cffipb.DeleteVisitsResponse{Ok: ok}, nil
+}
+
+// ClearUser implements cffipb.VisitServiceServer.
+func (s *Server) ClearUser(ctx context.Context, req *cffipb.ClearUserRequest) (*cffipb.ClearUserResponse, error) {
+	if err := s.vm.ClearUserCtx(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+	return &cffipb.ClearUserResponse{}, nil
+}
+
+// WatchUser implements cffipb.VisitServiceServer by streaming newly added
+// visits for req.UserId until the stream's context is canceled.
+func (s *Server) WatchUser(req *cffipb.WatchUserRequest, stream cffipb.VisitService_WatchUserServer) error {
+	return watchUser(stream.Context(), s.vm, req.GetUserId(), func(v cffi.Visit) error {
+		return stream.Send(toPBVisit(v))
+	})
+}
+
+func toPBVisit(v cffi.Visit) *cffipb.Visit {
+	ts, _ := ptypes.TimestampProto(v.Time)
+	return &cffipb.Visit{VisitId: v.VisitID, Url: v.URL, Text: v.Text, Time: ts}
+}
+
+func toPBVisits(visits []cffi.Visit) []*cffipb.Visit {
+	out := make([]*cffipb.Visit, len(visits))
+	for i, v := range visits {
+		out[i] = toPBVisit(v)
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"cffi"
+	"context"
+	"time"
+)
+
+// watchPollInterval is how often watchUser checks for new visits. The
+// underlying stores have no native subscribe mechanism, so WatchUser is
+// implemented by polling and diffing against previously seen visit IDs.
+const watchPollInterval = 500 * time.Millisecond
+
+// watchUser calls emit, oldest first, for every visit added for userID
+// after watchUser starts, until ctx is canceled or emit returns an error.
+func watchUser(ctx context.Context, vm *cffi.VisitManager, userID uint32, emit func(cffi.Visit) error) error {
+	seen := make(map[uint32]bool)
+	if initial, err := vm.GetRecentVisitsCtx(ctx, userID); err == nil {
+		for _, v := range initial {
+			seen[v.VisitID] = true
+		}
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			visits, err := vm.GetRecentVisitsCtx(ctx, userID)
+			if err != nil {
+				return err
+			}
+			// visits is newest-first; emit unseen ones oldest-first.
+			for i := len(visits) - 1; i >= 0; i-- {
+				v := visits[i]
+				if seen[v.VisitID] {
+					continue
+				}
+				seen[v.VisitID] = true
+				if err := emit(v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cffi
+
+// Storage is the backend used by a VisitManager to persist and retrieve
+// visits. VisitManager's per-user shard locks serialize calls for the same
+// userID only; calls for users on different shards can reach a Storage
+// concurrently, so an implementation must guard any state shared across
+// users itself (as goStorage does with its mutex).
+type Storage interface {
+	// AddVisit records a visit for userID, returning false on failure.
+	AddVisit(userID, visitID uint32, url, text string) bool
+	// RecentVisits returns the visits currently retained for userID.
+	RecentVisits(userID uint32) ([]Visit, error)
+	// RecentVisitsPage returns up to limit visits for userID, newest first,
+	// starting at the given 0-based offset, without necessarily
+	// materializing the full result set for userID.
+	RecentVisitsPage(userID uint32, offset, limit int) ([]Visit, error)
+	// Delete removes the given visit IDs for userID, returning false on failure.
+	Delete(userID uint32, visitIDs []uint32) bool
+	// Clear removes all visits retained for userID.
+	Clear(userID uint32)
+	// Close releases any resources held by the backend.
+	Close()
+}
+
+// Backend selects the Storage implementation used by a VisitManager.
+type Backend int
+
+const (
+	// BackendCGO stores visits in the C recent_visits store via cgo. This
+	// is the default and matches the module's original on-disk format.
+	BackendCGO Backend = iota
+	// BackendGo stores visits in a pure-Go, cgo-free store. Use this on
+	// platforms without a C toolchain, or under `go test -race`, which
+	// cgo does not support well.
+	BackendGo
+)
+
+// Options configures a new VisitManager.
+type Options struct {
+	// File is the path to the on-disk store.
+	File string
+	// MaxVisitsPerUser bounds how many visits are retained per user.
+	MaxVisitsPerUser int
+	// Backend selects the storage implementation. The zero value is BackendCGO.
+	Backend Backend
+}
+
+func newStorage(opts Options) (Storage, error) {
+	switch opts.Backend {
+	case BackendGo:
+		return newGoStorage(opts.File, opts.MaxVisitsPerUser)
+	default:
+		return newCgoStorage(opts.File, opts.MaxVisitsPerUser)
+	}
+}
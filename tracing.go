@@ -0,0 +1,29 @@
+package cffi
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer emits spans for VisitManager's per-user operations (AddVisit,
+// GetRecentVisits, GetRecentVisitsPage, DeleteVisits, ClearUser and their
+// Ctx variants); Close, ExportAll and ImportAll start no span. cgo calls are
+// opaque to Go profilers, so these spans are the primary way to see which
+// users or operations are slow.
+var tracer = otel.Tracer("cffi")
+
+// startSpan starts a span named "VisitManager.<method>" tagged with userID,
+// returning the derived context and span. Callers must call span.End().
+func startSpan(ctx context.Context, method string, userID uint32) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "VisitManager."+method, trace.WithAttributes(
+		attribute.Int64("user_id", int64(userID)),
+	))
+}
+
+// resultCountAttr tags a span with the number of visits an operation returned.
+func resultCountAttr(n int) attribute.KeyValue {
+	return attribute.Int("result_count", n)
+}